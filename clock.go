@@ -0,0 +1,17 @@
+package kocache
+
+import "time"
+
+// Clock abstracts time so a Cache's expiration and timeout behavior can be
+// driven deterministically in tests instead of relying on real sleeps. See
+// the fakeclock package for a controllable implementation.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                        { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
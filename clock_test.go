@@ -0,0 +1,56 @@
+package kocache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knightso/kocache/fakeclock"
+)
+
+func TestGetWithTimeoutUsesInjectedClock(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("key") // never resolved
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := cache.GetWithTimeout("key", 10*time.Millisecond)
+		result <- err
+	}()
+
+	// give the goroutine a chance to start waiting on the fake clock's After
+	// before advancing it past the timeout, without depending on wall time
+	// elapsing for the timeout itself.
+	time.Sleep(10 * time.Millisecond)
+	clock.Advance(10 * time.Millisecond)
+
+	if err := <-result; err != ErrGetCacheTimeout {
+		t.Fatalf("expected ErrGetCacheTimeout, got %v", err)
+	}
+}
+
+func TestClockDrivesExpiration(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.ReserveWithLifetime("key", 10*time.Millisecond)("value", nil)
+
+	if _, err := cache.Get("key"); err != nil {
+		t.Fatalf("expected hit before expiration, got %v", err)
+	}
+
+	clock.Advance(11 * time.Millisecond)
+
+	if _, err := cache.Get("key"); err != ErrExpired {
+		t.Fatalf("expected ErrExpired after advancing past the lifetime, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package kocache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultEventBufferSize is the size of the buffered channel events are
+	// queued on between Cache methods and the dispatcher goroutine started
+	// by Subscribe.
+	DefaultEventBufferSize = 256
+)
+
+// EventKind identifies what happened to an entry in an Event.
+type EventKind int
+
+const (
+	// EventInsert fires when Reserve (or a variant) adds a new entry to the cache.
+	EventInsert EventKind = iota
+	// EventResolve fires when a reserved entry is successfully resolved.
+	EventResolve
+	// EventHit fires when Get (or GetWithTimeout) successfully returns a value.
+	EventHit
+	// EventMiss fires when Get (or GetWithTimeout) finds no entry for a key.
+	EventMiss
+	// EventExpire fires when the background janitor removes an expired
+	// entry, and on every Get (or GetWithTimeout) that finds its entry
+	// expired. Get does not remove the entry, so it fires again on each
+	// such read.
+	EventExpire
+	// EventEvict fires when an entry is removed by the eviction policy to
+	// make room for another one.
+	EventEvict
+)
+
+// String returns a human-readable name for k.
+func (k EventKind) String() string {
+	switch k {
+	case EventInsert:
+		return "Insert"
+	case EventResolve:
+		return "Resolve"
+	case EventHit:
+		return "Hit"
+	case EventMiss:
+		return "Miss"
+	case EventExpire:
+		return "Expire"
+	case EventEvict:
+		return "Evict"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes a single lifecycle occurrence for a Cache entry, delivered
+// to subscribers registered via Cache.Subscribe.
+type Event[K comparable, V any] struct {
+	Kind  EventKind
+	Key   K
+	Value V
+	Time  time.Time
+}
+
+// Subscribe registers fn to be called for every Event the Cache produces,
+// from a single dispatcher goroutine shared by all subscribers, and returns
+// a function that unregisters it. fn must not block: events are delivered
+// through a bounded channel, and a slow subscriber causes events to be
+// dropped (oldest first) for every subscriber, counted in Stats.EventDrops.
+// The dispatcher goroutine itself is started on the first call to Subscribe,
+// so a Cache that never subscribes never pays for it.
+func (c *Cache[K, V]) Subscribe(fn func(Event[K, V])) (unsubscribe func()) {
+	c.startEventDispatcher()
+
+	id := atomic.AddUint64(&c.subSeq, 1)
+
+	c.subsMu.Lock()
+	if c.subs == nil {
+		c.subs = make(map[uint64]func(Event[K, V]))
+	}
+	c.subs[id] = fn
+	c.subsMu.Unlock()
+
+	return func() {
+		c.subsMu.Lock()
+		delete(c.subs, id)
+		c.subsMu.Unlock()
+	}
+}
+
+// startEventDispatcher starts dispatchEvents, exactly once per Cache,
+// whether triggered by the first Subscribe or by Stop (so Stop always has a
+// dispatcher listening on eventsStopped to shut down and signal eventsDone,
+// even if Subscribe was never called).
+func (c *Cache[K, V]) startEventDispatcher() {
+	c.eventsOnce.Do(func() {
+		go c.dispatchEvents()
+	})
+}
+
+// publish enqueues an Event for the dispatcher goroutine, dropping the
+// oldest queued event (and counting the drop) if the buffer is full.
+func (c *Cache[K, V]) publish(kind EventKind, key K, value V) {
+	c.subsMu.RLock()
+	empty := len(c.subs) == 0
+	c.subsMu.RUnlock()
+	if empty {
+		return
+	}
+
+	ev := Event[K, V]{Kind: kind, Key: key, Value: value, Time: time.Now()}
+
+	select {
+	case c.events <- ev:
+		return
+	default:
+	}
+
+	select {
+	case <-c.events:
+		atomic.AddUint64(&c.stats.EventDrops, 1)
+	default:
+	}
+
+	select {
+	case c.events <- ev:
+	default:
+		atomic.AddUint64(&c.stats.EventDrops, 1)
+	}
+}
+
+// dispatchEvents delivers events queued by publish to every current
+// subscriber, one at a time, until stopped.
+func (c *Cache[K, V]) dispatchEvents() {
+	defer close(c.eventsDone)
+
+	for {
+		select {
+		case ev := <-c.events:
+			c.subsMu.RLock()
+			for _, fn := range c.subs {
+				fn(ev)
+			}
+			c.subsMu.RUnlock()
+		case <-c.eventsStopped:
+			return
+		}
+	}
+}
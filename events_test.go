@@ -0,0 +1,127 @@
+package kocache
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubscribeReceivesLifecycleEvents(t *testing.T) {
+	cache, err := New[string, string](WithSize[string, string](1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	var mu sync.Mutex
+	var kinds []EventKind
+
+	unsubscribe := cache.Subscribe(func(ev Event[string, string]) {
+		mu.Lock()
+		kinds = append(kinds, ev.Kind)
+		mu.Unlock()
+	})
+	defer unsubscribe()
+
+	cache.Reserve("a")("1", nil)
+	if _, err := cache.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get("missing"); err != ErrEntryNotFound {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+	// evicts "a" since size is 1
+	cache.Reserve("b")("2", nil)
+
+	deadline := time.Now().Add(time.Second)
+	want := []EventKind{EventInsert, EventResolve, EventHit, EventMiss, EventInsert, EventEvict, EventResolve}
+	for {
+		mu.Lock()
+		got := len(kinds)
+		mu.Unlock()
+		if got >= len(want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for events, got %v", kinds)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, k := range want {
+		if kinds[i] != k {
+			t.Fatalf("event %d: expected %s, got %s (all: %v)", i, k, kinds[i], kinds)
+		}
+	}
+}
+
+func TestUnsubscribeStopsDelivery(t *testing.T) {
+	cache, err := New[string, string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	var count int32
+	unsubscribe := cache.Subscribe(func(ev Event[string, string]) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	cache.Reserve("a")("1", nil)
+	time.Sleep(20 * time.Millisecond)
+
+	unsubscribe()
+	before := atomic.LoadInt32(&count)
+
+	cache.Reserve("b")("2", nil)
+	time.Sleep(20 * time.Millisecond)
+
+	if after := atomic.LoadInt32(&count); after != before {
+		t.Fatalf("expected no events after unsubscribe, got %d more", after-before)
+	}
+}
+
+// TestNoEventDispatcherGoroutineWithoutSubscribe guards against the event
+// dispatcher running for the lifetime of every Cache regardless of whether
+// anything ever calls Subscribe: it must start lazily, not unconditionally
+// from New.
+func TestNoEventDispatcherGoroutineWithoutSubscribe(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	cache, err := New[string, string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("a")("1", nil)
+	if _, err := cache.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("expected no event dispatcher goroutine without Subscribe (before:%d, after:%d)", before, after)
+	}
+}
+
+func TestStopShutsDownEventDispatcher(t *testing.T) {
+	cache, err := New[string, string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Stop()
+	cache.Stop() // must be safe to call twice
+
+	select {
+	case <-cache.eventsDone:
+	default:
+		t.Fatal("event dispatcher goroutine did not shut down")
+	}
+}
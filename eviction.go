@@ -0,0 +1,294 @@
+package kocache
+
+import "container/list"
+
+// EvictionPolicy decides which key a Cache should evict when it grows over
+// capacity. Implementations are not safe for concurrent use; Cache
+// serializes all calls into the policy with its own lock.
+type EvictionPolicy[K comparable] interface {
+	// OnAdd notifies the policy that key was inserted (or re-reserved),
+	// consuming the given weight of capacity.
+	OnAdd(key K, weight int)
+	// OnAccess notifies the policy that key was read via Get.
+	OnAccess(key K)
+	// OnRemove notifies the policy that key was removed from the cache
+	// other than through Evict, e.g. because it expired or was replaced.
+	// Cache does not call it for keys returned by Evict.
+	OnRemove(key K)
+	// Evict selects and removes the next key the policy considers least
+	// valuable, returning ok=false if the policy holds no keys.
+	Evict() (key K, ok bool)
+}
+
+// lruItem is the value stored in a lruPolicy's list.
+type lruItem[K comparable] struct {
+	key    K
+	weight int
+}
+
+// lruPolicy is the classic least-recently-used policy: OnAccess moves a key
+// to the front, Evict removes from the back.
+type lruPolicy[K comparable] struct {
+	ll    *list.List
+	elems map[K]*list.Element
+}
+
+// NewLRUPolicy returns an EvictionPolicy that evicts the least recently used key.
+// This is kocache's default policy.
+func NewLRUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lruPolicy[K]{
+		ll:    list.New(),
+		elems: make(map[K]*list.Element),
+	}
+}
+
+func (p *lruPolicy[K]) OnAdd(key K, weight int) {
+	if el, ok := p.elems[key]; ok {
+		el.Value.(*lruItem[K]).weight = weight
+		p.ll.MoveToFront(el)
+		return
+	}
+	p.elems[key] = p.ll.PushFront(&lruItem[K]{key: key, weight: weight})
+}
+
+func (p *lruPolicy[K]) OnAccess(key K) {
+	if el, ok := p.elems[key]; ok {
+		p.ll.MoveToFront(el)
+	}
+}
+
+func (p *lruPolicy[K]) OnRemove(key K) {
+	if el, ok := p.elems[key]; ok {
+		p.ll.Remove(el)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lruPolicy[K]) Evict() (key K, ok bool) {
+	el := p.ll.Back()
+	if el == nil {
+		return key, false
+	}
+	item := el.Value.(*lruItem[K])
+	p.ll.Remove(el)
+	delete(p.elems, item.key)
+	return item.key, true
+}
+
+// freqNode groups all keys currently sharing the same access frequency.
+type freqNode[K comparable] struct {
+	freq int
+	keys *list.List // of *lfuEntry[K]
+}
+
+// lfuEntry tracks a single key's position within the frequency list.
+type lfuEntry[K comparable] struct {
+	key     K
+	weight  int
+	parent  *list.Element // element in lfuPolicy.freqs holding this entry's *freqNode
+	keyElem *list.Element // this entry's element within parent's freqNode.keys
+}
+
+// lfuPolicy is a least-frequently-used policy backed by a doubly-linked list
+// of frequency nodes, each holding the keys currently at that frequency.
+// Every operation is O(1): OnAccess moves the entry to the next-higher
+// frequency node, creating it if needed, and Evict pops a key from the head
+// (lowest-frequency) node.
+type lfuPolicy[K comparable] struct {
+	freqs   *list.List // of *freqNode[K], ascending frequency
+	entries map[K]*lfuEntry[K]
+}
+
+// NewLFUPolicy returns an EvictionPolicy that evicts the least frequently used key.
+func NewLFUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{
+		freqs:   list.New(),
+		entries: make(map[K]*lfuEntry[K]),
+	}
+}
+
+func (p *lfuPolicy[K]) OnAdd(key K, weight int) {
+	if e, ok := p.entries[key]; ok {
+		e.weight = weight
+		return
+	}
+
+	front := p.freqs.Front()
+	var fn *freqNode[K]
+	var parent *list.Element
+	if front != nil && front.Value.(*freqNode[K]).freq == 1 {
+		parent = front
+		fn = front.Value.(*freqNode[K])
+	} else {
+		fn = &freqNode[K]{freq: 1, keys: list.New()}
+		parent = p.freqs.PushFront(fn)
+	}
+
+	e := &lfuEntry[K]{key: key, weight: weight, parent: parent}
+	e.keyElem = fn.keys.PushBack(e)
+	p.entries[key] = e
+}
+
+func (p *lfuPolicy[K]) OnAccess(key K) {
+	e, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	cur := e.parent
+	curNode := cur.Value.(*freqNode[K])
+	curNode.keys.Remove(e.keyElem)
+
+	nextFreq := curNode.freq + 1
+	next := cur.Next()
+	var nextNode *freqNode[K]
+	if next != nil && next.Value.(*freqNode[K]).freq == nextFreq {
+		nextNode = next.Value.(*freqNode[K])
+	} else {
+		nextNode = &freqNode[K]{freq: nextFreq, keys: list.New()}
+		next = p.freqs.InsertAfter(nextNode, cur)
+	}
+
+	e.parent = next
+	e.keyElem = nextNode.keys.PushBack(e)
+
+	if curNode.keys.Len() == 0 {
+		p.freqs.Remove(cur)
+	}
+}
+
+func (p *lfuPolicy[K]) OnRemove(key K) {
+	e, ok := p.entries[key]
+	if !ok {
+		return
+	}
+
+	node := e.parent.Value.(*freqNode[K])
+	node.keys.Remove(e.keyElem)
+	if node.keys.Len() == 0 {
+		p.freqs.Remove(e.parent)
+	}
+	delete(p.entries, key)
+}
+
+func (p *lfuPolicy[K]) Evict() (key K, ok bool) {
+	front := p.freqs.Front()
+	if front == nil {
+		return key, false
+	}
+
+	node := front.Value.(*freqNode[K])
+	elem := node.keys.Front()
+	if elem == nil {
+		return key, false
+	}
+
+	e := elem.Value.(*lfuEntry[K])
+	node.keys.Remove(elem)
+	if node.keys.Len() == 0 {
+		p.freqs.Remove(front)
+	}
+	delete(p.entries, e.key)
+
+	return e.key, true
+}
+
+// slruItem tracks a key's position in one of slruPolicy's two segments.
+type slruItem[K comparable] struct {
+	key       K
+	weight    int
+	protected bool
+	elem      *list.Element
+}
+
+// slruPolicy is a segmented-LRU policy with a probationary segment for newly
+// added or once-accessed keys and a protected segment for keys accessed
+// again out of probation. Eviction always prefers the probationary segment
+// so that a single scan of cold keys cannot flush out hot ones.
+type slruPolicy[K comparable] struct {
+	protectedCap int
+	probation    *list.List
+	protected    *list.List
+	elems        map[K]*slruItem[K]
+}
+
+// NewSLRUPolicy returns a segmented-LRU EvictionPolicy sized for a cache of
+// the given capacity, with 80% of it reserved as the protected segment and
+// the remainder as the probationary segment.
+func NewSLRUPolicy[K comparable](size int) EvictionPolicy[K] {
+	protectedCap := size * 4 / 5
+	if protectedCap < 1 {
+		protectedCap = 1
+	}
+	return &slruPolicy[K]{
+		protectedCap: protectedCap,
+		probation:    list.New(),
+		protected:    list.New(),
+		elems:        make(map[K]*slruItem[K]),
+	}
+}
+
+func (p *slruPolicy[K]) OnAdd(key K, weight int) {
+	if it, ok := p.elems[key]; ok {
+		it.weight = weight
+		return
+	}
+	it := &slruItem[K]{key: key, weight: weight}
+	it.elem = p.probation.PushFront(it)
+	p.elems[key] = it
+}
+
+func (p *slruPolicy[K]) OnAccess(key K) {
+	it, ok := p.elems[key]
+	if !ok {
+		return
+	}
+
+	if it.protected {
+		p.protected.MoveToFront(it.elem)
+		return
+	}
+
+	p.probation.Remove(it.elem)
+	it.protected = true
+	it.elem = p.protected.PushFront(it)
+
+	if p.protected.Len() > p.protectedCap {
+		back := p.protected.Back()
+		demoted := back.Value.(*slruItem[K])
+		p.protected.Remove(back)
+		demoted.protected = false
+		demoted.elem = p.probation.PushFront(demoted)
+	}
+}
+
+func (p *slruPolicy[K]) OnRemove(key K) {
+	it, ok := p.elems[key]
+	if !ok {
+		return
+	}
+	if it.protected {
+		p.protected.Remove(it.elem)
+	} else {
+		p.probation.Remove(it.elem)
+	}
+	delete(p.elems, key)
+}
+
+func (p *slruPolicy[K]) Evict() (key K, ok bool) {
+	target := p.probation
+	elem := target.Back()
+	if elem == nil {
+		target = p.protected
+		elem = target.Back()
+	}
+	if elem == nil {
+		return key, false
+	}
+
+	it := elem.Value.(*slruItem[K])
+	target.Remove(elem)
+	delete(p.elems, it.key)
+
+	return it.key, true
+}
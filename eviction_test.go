@@ -0,0 +1,189 @@
+package kocache
+
+import "testing"
+
+func TestCacheWithEvictionPolicySelectsLFU(t *testing.T) {
+	cache, err := New(
+		WithSize[string, string](2),
+		WithEvictionPolicy[string, string](NewLFUPolicy[string]()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("1")("value1", nil)
+	cache.Reserve("2")("value2", nil)
+
+	// access "1" twice, leaving "2" at its original, lowest frequency.
+	if _, err := cache.Get("1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Reserve("3")("value3", nil) // over capacity: LFU should drop "2", not "1"
+
+	if _, err := cache.Get("2"); err != ErrEntryNotFound {
+		t.Fatalf("expected \"2\" to be evicted by LFU, but was:%v", err)
+	}
+	if _, err := cache.Get("1"); err != nil {
+		t.Fatalf("expected \"1\" to survive LFU eviction, but was:%v", err)
+	}
+}
+
+func TestCacheWithOnEvictInvokedOnCapacityEviction(t *testing.T) {
+	var evictedKey, evictedValue string
+	calls := 0
+
+	cache, err := New(
+		WithSize[string, string](1),
+		WithOnEvict[string, string](func(k, v string) {
+			calls++
+			evictedKey, evictedValue = k, v
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("1")("value1", nil)
+	cache.Reserve("2")("value2", nil) // evicts "1" to stay within size 1
+
+	if actual, expected := calls, 1; actual != expected {
+		t.Fatalf("onEvict calls - expected:%d, but was:%d", expected, actual)
+	}
+	if actual, expected := evictedKey, "1"; actual != expected {
+		t.Fatalf("evicted key - expected:%s, but was:%s", expected, actual)
+	}
+	if actual, expected := evictedValue, "value1"; actual != expected {
+		t.Fatalf("evicted value - expected:%s, but was:%s", expected, actual)
+	}
+}
+
+func TestCacheReserveWithWeightConsumesCapacity(t *testing.T) {
+	cache, err := New(WithSize[string, int](10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("small")(1, nil)
+	cache.ReserveWithWeight("big", 9)(2, nil) // pushes total weight to 10, still within size
+
+	if actual, expected := cache.Len(), 2; actual != expected {
+		t.Fatalf("expected:%d, but was:%d", expected, actual)
+	}
+
+	cache.ReserveWithWeight("bigger", 9)(3, nil) // weight 9 forces both prior entries out
+
+	if _, err := cache.Get("small"); err != ErrEntryNotFound {
+		t.Fatalf("expected \"small\" to be evicted to make room, but was:%v", err)
+	}
+	if _, err := cache.Get("big"); err != ErrEntryNotFound {
+		t.Fatalf("expected \"big\" to be evicted to make room, but was:%v", err)
+	}
+
+	value, err := cache.Get("bigger")
+	if err != nil {
+		t.Fatalf("expected \"bigger\" to still be present, but was:%v", err)
+	}
+	if actual, expected := value, 3; actual != expected {
+		t.Fatalf("expected:%d, but was:%d", expected, actual)
+	}
+}
+
+func TestLRUPolicy(t *testing.T) {
+	p := NewLRUPolicy[string]()
+
+	p.OnAdd("1", 1)
+	p.OnAdd("2", 1)
+	p.OnAdd("3", 1)
+
+	p.OnAccess("1") // "1" is now most recently used
+
+	if key, ok := p.Evict(); !ok || key != "2" {
+		t.Fatalf("expected to evict 2, got %v, ok=%v", key, ok)
+	}
+
+	if key, ok := p.Evict(); !ok || key != "3" {
+		t.Fatalf("expected to evict 3, got %v, ok=%v", key, ok)
+	}
+
+	if key, ok := p.Evict(); !ok || key != "1" {
+		t.Fatalf("expected to evict 1, got %v, ok=%v", key, ok)
+	}
+
+	if _, ok := p.Evict(); ok {
+		t.Fatal("expected no more keys to evict")
+	}
+}
+
+func TestLFUPolicy(t *testing.T) {
+	p := NewLFUPolicy[string]()
+
+	p.OnAdd("1", 1)
+	p.OnAdd("2", 1)
+	p.OnAdd("3", 1)
+
+	p.OnAccess("1")
+	p.OnAccess("1")
+	p.OnAccess("2")
+
+	// "3" has freq 1, the lowest, so it is evicted first.
+	if key, ok := p.Evict(); !ok || key != "3" {
+		t.Fatalf("expected to evict 3, got %v, ok=%v", key, ok)
+	}
+
+	// "2" has freq 2, "1" has freq 3.
+	if key, ok := p.Evict(); !ok || key != "2" {
+		t.Fatalf("expected to evict 2, got %v, ok=%v", key, ok)
+	}
+
+	if key, ok := p.Evict(); !ok || key != "1" {
+		t.Fatalf("expected to evict 1, got %v, ok=%v", key, ok)
+	}
+}
+
+func TestSLRUPolicy(t *testing.T) {
+	p := NewSLRUPolicy[string](5)
+
+	p.OnAdd("1", 1)
+	p.OnAdd("2", 1)
+
+	// promote "1" into the protected segment
+	p.OnAccess("1")
+
+	// probationary "2" is still colder than protected "1"
+	if key, ok := p.Evict(); !ok || key != "2" {
+		t.Fatalf("expected to evict 2, got %v, ok=%v", key, ok)
+	}
+
+	if key, ok := p.Evict(); !ok || key != "1" {
+		t.Fatalf("expected to evict 1, got %v, ok=%v", key, ok)
+	}
+
+	if _, ok := p.Evict(); ok {
+		t.Fatal("expected no more keys to evict")
+	}
+}
+
+func TestSLRUPolicyDemotesOnProtectedOverflow(t *testing.T) {
+	p := NewSLRUPolicy[string](2) // protectedCap == 1
+
+	p.OnAdd("1", 1)
+	p.OnAdd("2", 1)
+
+	p.OnAccess("1") // promotes "1" to protected
+	p.OnAccess("2") // promotes "2" to protected, demoting "1" back to probation
+
+	if key, ok := p.Evict(); !ok || key != "1" {
+		t.Fatalf("expected demoted 1 to be evicted first, got %v, ok=%v", key, ok)
+	}
+
+	if key, ok := p.Evict(); !ok || key != "2" {
+		t.Fatalf("expected to evict 2, got %v, ok=%v", key, ok)
+	}
+}
@@ -0,0 +1,141 @@
+package kocache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knightso/kocache/fakeclock"
+)
+
+func TestSlidingExpiration(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithSlidingExpiration[string, string](50*time.Millisecond), WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("key")("value", nil)
+
+	// keep reading well within the sliding window; each Get should push the
+	// deadline back out, so the entry must never expire.
+	for i := 0; i < 15; i++ {
+		if _, err := cache.Get("key"); err != nil {
+			t.Fatalf("expected entry to stay alive under repeated access, but was:%v", err)
+		}
+		clock.Advance(10 * time.Millisecond)
+	}
+
+	// now stop touching it; it must expire once the sliding window elapses.
+	clock.Advance(51 * time.Millisecond)
+	if _, err := cache.Get("key"); err != ErrExpired {
+		t.Fatalf("ErrExpired expected, but was:%v", err)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithDefaultLifetime[string, string](20*time.Millisecond), WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("key")("value", nil)
+
+	if !cache.Touch("key", 200*time.Millisecond) {
+		t.Fatal("expected Touch to find key")
+	}
+
+	clock.Advance(50 * time.Millisecond)
+
+	if _, err := cache.Get("key"); err != nil {
+		t.Fatalf("expected Touch to have extended the lifetime, but was:%v", err)
+	}
+
+	if cache.Touch("missing", time.Minute) {
+		t.Fatal("expected Touch to return false for a missing key")
+	}
+}
+
+func TestReserveWithExpireAt(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.ReserveWithExpireAt("key", clock.Now().Add(30*time.Millisecond))("value", nil)
+
+	if value, err := cache.Get("key"); err != nil || value != "value" {
+		t.Fatalf("expected value, err=nil, but was:%s, %v", value, err)
+	}
+
+	clock.Advance(31 * time.Millisecond)
+
+	if _, err := cache.Get("key"); err != ErrExpired {
+		t.Fatalf("ErrExpired expected, but was:%v", err)
+	}
+}
+
+// TestReserveWithExpireAtSingleFlightBeforeResolve guards against expireAt
+// being visible before the reservation resolves: a concurrent Get must block
+// on the in-flight fetch like every other Reserve variant, not see the
+// not-yet-resolved entry as already expired.
+func TestReserveWithExpireAtSingleFlightBeforeResolve(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	// already in the past at reservation time: if expireAt were visible
+	// before resolve, GetWithTimeout would report ErrExpired immediately
+	// instead of timing out while waiting on the still in-flight reservation.
+	cache.ReserveWithExpireAt("key", clock.Now().Add(-time.Millisecond)) // never resolved
+
+	if _, err := cache.GetWithTimeout("key", 0); err != ErrGetCacheTimeout {
+		t.Fatalf("expected ErrGetCacheTimeout while the reservation is in flight, but was:%v", err)
+	}
+}
+
+// TestReserveWithExpireAtNotSweptByJanitorBeforeResolve guards against the
+// janitor scheduling (and sweeping) an entry reserved via ReserveWithExpireAt
+// before it resolves: the value must still become visible once resolve is
+// finally called.
+func TestReserveWithExpireAtNotSweptByJanitorBeforeResolve(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(
+		WithJanitor[string, string](time.Millisecond),
+		WithClock[string, string](clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	resolve := cache.ReserveWithExpireAt("key", clock.Now().Add(5*time.Millisecond))
+
+	// advance well past the expireAt before resolving: if expireAt (and the
+	// janitor's expiry-heap entry for it) were visible pre-resolve, the
+	// janitor would have swept "key" from c.items by now.
+	for i := 0; i < 20; i++ {
+		clock.Advance(time.Millisecond)
+		time.Sleep(time.Millisecond)
+	}
+	if actual, expected := cache.Len(), 1; actual != expected {
+		t.Fatalf("expected entry not to be swept by the janitor before resolve, but Len()=%d", actual)
+	}
+
+	resolve("value", nil)
+
+	// "at" has since passed, so the entry is correctly expired now that it
+	// is resolved - the bug this guards against is ErrEntryNotFound (the
+	// entry silently missing, having been swept before resolve), not
+	// ErrExpired.
+	if _, err := cache.Get("key"); err != ErrExpired {
+		t.Fatalf("ErrExpired expected, but was:%v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package kocache
+
+// expItem is one key's position in an expQueue.
+type expItem[K comparable, V any] struct {
+	key   K
+	ent   *entry[V]
+	index int // index within expQueue.items, maintained by container/heap
+}
+
+// expQueue is a container/heap min-heap of cache entries ordered by
+// expireAt, letting the janitor find the next entry due to expire in O(1)
+// and react to Reserve/Touch/eviction in O(log n).
+type expQueue[K comparable, V any] struct {
+	items []*expItem[K, V]
+}
+
+func (q *expQueue[K, V]) Len() int { return len(q.items) }
+
+func (q *expQueue[K, V]) Less(i, j int) bool {
+	return q.items[i].ent.expireAtSnapshot().Before(q.items[j].ent.expireAtSnapshot())
+}
+
+func (q *expQueue[K, V]) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *expQueue[K, V]) Push(x any) {
+	item := x.(*expItem[K, V])
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+func (q *expQueue[K, V]) Pop() any {
+	old := q.items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	q.items = old[:n-1]
+	return item
+}
+
+// Peek returns the entry with the earliest expireAt, or nil if the queue is empty.
+func (q *expQueue[K, V]) Peek() *expItem[K, V] {
+	if len(q.items) == 0 {
+		return nil
+	}
+	return q.items[0]
+}
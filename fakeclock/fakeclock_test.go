@@ -0,0 +1,59 @@
+package fakeclock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowReflectsAdvance(t *testing.T) {
+	start := time.Unix(0, 0)
+	c := New(start)
+
+	if got := c.Now(); !got.Equal(start) {
+		t.Fatalf("expected %v, got %v", start, got)
+	}
+
+	c.Advance(5 * time.Second)
+
+	if want, got := start.Add(5*time.Second), c.Now(); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAfterFiresOnlyOnceDeadlinePasses(t *testing.T) {
+	c := New(time.Unix(0, 0))
+
+	ch := c.After(10 * time.Millisecond)
+
+	select {
+	case <-ch:
+		t.Fatal("After fired before its deadline")
+	default:
+	}
+
+	c.Advance(5 * time.Millisecond)
+
+	select {
+	case <-ch:
+		t.Fatal("After fired before its deadline")
+	default:
+	}
+
+	c.Advance(5 * time.Millisecond)
+
+	select {
+	case <-ch:
+	default:
+		t.Fatal("After did not fire once its deadline passed")
+	}
+}
+
+func TestAfterWithNonPositiveDurationFiresImmediately(t *testing.T) {
+	c := New(time.Unix(0, 0))
+
+	select {
+	case <-c.After(0):
+	default:
+		t.Fatal("After(0) did not fire immediately")
+	}
+}
@@ -0,0 +1,127 @@
+package kocache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// FSStore is a Store backed by the local filesystem. Keys are hashed and
+// sharded into two levels of subdirectories so that a single directory never
+// accumulates an unbounded number of entries.
+type FSStore struct {
+	dir  string
+	size int64
+}
+
+// NewFSStore creates an FSStore rooted at dir, creating it if necessary, and
+// walks any existing entries under dir to seed Size().
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "kocache: creating FSStore directory")
+	}
+
+	s := &FSStore{dir: dir}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			atomic.AddInt64(&s.size, info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "kocache: scanning FSStore directory")
+	}
+
+	return s, nil
+}
+
+// path shards key into dir/ab/cd/<hash>, using the first four hex digits of
+// its SHA-256 hash as the shard path so no single directory holds every entry.
+func (s *FSStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+	return filepath.Join(s.dir, hash[0:2], hash[2:4], hash)
+}
+
+// Get implements Store.
+func (s *FSStore) Get(key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrEntryNotFound
+		}
+		return nil, errors.Wrap(err, "kocache: opening FSStore entry")
+	}
+	return f, nil
+}
+
+// Put implements Store.
+func (s *FSStore) Put(key string, r io.Reader) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "kocache: creating FSStore shard directory")
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return errors.Wrap(err, "kocache: creating FSStore temp file")
+	}
+
+	n, err := io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "kocache: writing FSStore entry")
+	}
+
+	if old, statErr := os.Stat(path); statErr == nil {
+		atomic.AddInt64(&s.size, -old.Size())
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "kocache: committing FSStore entry")
+	}
+
+	atomic.AddInt64(&s.size, n)
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *FSStore) Delete(key string) error {
+	path := s.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "kocache: stat FSStore entry")
+	}
+
+	if err := os.Remove(path); err != nil {
+		return errors.Wrap(err, "kocache: removing FSStore entry")
+	}
+
+	atomic.AddInt64(&s.size, -info.Size())
+
+	return nil
+}
+
+// Size implements Store.
+func (s *FSStore) Size() int64 {
+	return atomic.LoadInt64(&s.size)
+}
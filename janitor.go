@@ -0,0 +1,176 @@
+package kocache
+
+import (
+	"container/heap"
+	"sync/atomic"
+	"time"
+)
+
+// scheduleExpiryLocked inserts or repositions key's entry in c.expQ so its
+// heap position reflects ent.expireAt, returning true if ent is now the
+// earliest entry due to expire. c.mu must be held. It is a no-op if the
+// janitor is not enabled or ent never expires.
+func (c *Cache[K, V]) scheduleExpiryLocked(key K, ent *entry[V]) bool {
+	if c.expQ == nil || ent.expireAtSnapshot().IsZero() {
+		return false
+	}
+
+	item, ok := c.expIndex[key]
+	if ok {
+		item.ent = ent
+		heap.Fix(c.expQ, item.index)
+	} else {
+		item = &expItem[K, V]{key: key, ent: ent}
+		heap.Push(c.expQ, item)
+		c.expIndex[key] = item
+	}
+
+	return c.expQ.items[0] == item
+}
+
+// scheduleExpiry is scheduleExpiryLocked for callers that do not already
+// hold c.mu; it wakes the janitor if the rescheduled entry is now the
+// earliest one due to expire.
+func (c *Cache[K, V]) scheduleExpiry(key K, ent *entry[V]) {
+	c.mu.Lock()
+	wake := c.scheduleExpiryLocked(key, ent)
+	c.mu.Unlock()
+
+	if wake {
+		c.wakeJanitor()
+	}
+}
+
+// unscheduleExpiryLocked removes key's entry from c.expQ, if present. c.mu
+// must be held.
+func (c *Cache[K, V]) unscheduleExpiryLocked(key K) {
+	if c.expQ == nil {
+		return
+	}
+
+	if item, ok := c.expIndex[key]; ok {
+		heap.Remove(c.expQ, item.index)
+		delete(c.expIndex, key)
+	}
+}
+
+// wakeJanitor nudges the janitor goroutine to re-check the queue instead of
+// sleeping until its current timer fires. It never blocks.
+func (c *Cache[K, V]) wakeJanitor() {
+	select {
+	case c.janitorWake <- struct{}{}:
+	default:
+	}
+}
+
+// runJanitor sleeps until the earliest entry in c.expQ is due, removes all
+// entries that are due, and repeats until Stop is called. It wakes early
+// whenever wakeJanitor signals that a new, earlier deadline was scheduled.
+// It sleeps via c.opts.clock.After rather than the real time package, so a
+// fake clock drives the janitor in lockstep with the expirations it fakes.
+func (c *Cache[K, V]) runJanitor(interval time.Duration) {
+	defer close(c.janitorDone)
+
+	for {
+		c.mu.Lock()
+		var nextEnt *entry[V]
+		if next := c.expQ.Peek(); next != nil {
+			nextEnt = next.ent
+		}
+		c.mu.Unlock()
+
+		wait := interval
+		if nextEnt != nil {
+			if d := nextEnt.expireAtSnapshot().Sub(c.opts.clock.Now()); d > 0 {
+				wait = d
+			} else {
+				wait = 0
+			}
+		}
+
+		if wait > 0 {
+			select {
+			case <-c.janitorStopped:
+				return
+			case <-c.janitorWake:
+				continue
+			case <-c.opts.clock.After(wait):
+			}
+		}
+
+		select {
+		case <-c.janitorStopped:
+			return
+		default:
+		}
+
+		c.removeExpired()
+	}
+}
+
+// removeExpired drops every entry in c.expQ whose deadline has passed. As
+// with a capacity-driven eviction, each dropped entry is passed to onEvict,
+// which runs after c.mu is released. Unlike a capacity-driven eviction, it
+// also deletes the entry's blob from the store.
+func (c *Cache[K, V]) removeExpired() {
+	now := c.opts.clock.Now()
+
+	c.mu.Lock()
+
+	var evicted []evictedEntry[K, V]
+	for {
+		item := c.expQ.Peek()
+		if item == nil || !item.ent.Expired(now) {
+			break
+		}
+
+		heap.Pop(c.expQ)
+		delete(c.expIndex, item.key)
+
+		cur, ok := c.items[item.key]
+		if !ok || cur != item.ent {
+			continue
+		}
+
+		delete(c.items, item.key)
+		c.weight -= cur.weight
+		c.policy.OnRemove(item.key)
+
+		evicted = append(evicted, evictedEntry[K, V]{key: item.key, value: cur.value})
+
+		if c.store != nil {
+			sk := storeKey(item.key)
+			c.storePolicy.OnRemove(sk)
+			delete(c.storeExpireAt, sk)
+			_ = c.store.Delete(sk)
+			atomic.StoreUint64(&c.stats.BytesInUse, uint64(c.store.Size()))
+		}
+
+		c.addStat(&c.stats.Expirations, 1)
+		c.publish(EventExpire, item.key, cur.value)
+	}
+
+	c.mu.Unlock()
+
+	c.notifyEvicted(evicted)
+}
+
+// Stop shuts down the background janitor started by WithJanitor and the
+// event dispatcher goroutine started by Subscribe, waiting for both to exit
+// if they were ever started. A Cache that uses neither WithJanitor nor
+// Subscribe carries no background goroutines and does not need Stop, but
+// calling it anyway is always safe, including more than once.
+func (c *Cache[K, V]) Stop() {
+	c.stopOnce.Do(func() {
+		if c.janitorStopped != nil {
+			close(c.janitorStopped)
+		}
+		c.startEventDispatcher()
+		close(c.eventsStopped)
+	})
+
+	if c.janitorDone != nil {
+		<-c.janitorDone
+	}
+	<-c.eventsDone
+}
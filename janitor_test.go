@@ -0,0 +1,122 @@
+package kocache
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/knightso/kocache/fakeclock"
+)
+
+// waitForLen advances clock in small steps, driving the janitor's fake-clock
+// sleep forward, until cache.Len() reaches want or a real wall-clock
+// deadline trips (guarding against the janitor never noticing).
+func waitForLen[K comparable, V any](t *testing.T, cache *Cache[K, V], clock *fakeclock.Clock, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Len() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for cache.Len() to reach %d, was:%d", want, cache.Len())
+		}
+		clock.Advance(5 * time.Millisecond)
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJanitorRemovesExpiredEntries(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithJanitor[string, string](5*time.Millisecond), WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.ReserveWithLifetime("short", 10*time.Millisecond)("value", nil)
+
+	if actual, expected := cache.Len(), 1; actual != expected {
+		t.Fatalf("expected:%d, but was:%d", expected, actual)
+	}
+
+	waitForLen(t, cache, clock, 0)
+}
+
+func TestJanitorWakesEarlyForSoonerDeadline(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithJanitor[string, string](time.Hour), WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	// with only the hour-long base interval, a naive janitor would not
+	// notice this within the test's lifetime; it must wake early instead.
+	cache.ReserveWithLifetime("soon", 10*time.Millisecond)("value", nil)
+
+	waitForLen(t, cache, clock, 0)
+}
+
+// TestJanitorSlidingExpirationNoRace exercises the janitor's expiry heap
+// concurrently with sliding-expiration touches on the same entry - a
+// combination that used to trip `go test -race`, since the heap read
+// entry.expireAt directly while entry.touch updated it under entry.mu.
+func TestJanitorSlidingExpirationNoRace(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(
+		WithJanitor[string, string](time.Millisecond),
+		WithSlidingExpiration[string, string](time.Hour),
+		WithClock[string, string](clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("key")("value", nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if _, err := cache.Get("key"); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		clock.Advance(time.Millisecond)
+	}
+
+	<-done
+}
+
+func TestStopShutsDownJanitorGoroutine(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	cache, err := New(WithJanitor[string, string](time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Stop()
+	cache.Stop() // must be safe to call twice
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("janitor goroutine still running after Stop (before:%d, now:%d)", before, runtime.NumGoroutine())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStopOnCacheWithoutJanitorIsNoop(t *testing.T) {
+	cache, err := New[string, string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Stop()
+}
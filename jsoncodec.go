@@ -0,0 +1,18 @@
+package kocache
+
+import "encoding/json"
+
+// JSONCodec is a Codec that serializes values with encoding/json. It is a
+// convenient default for WithStore when V is JSON-marshalable.
+type JSONCodec[V any] struct{}
+
+// Encode implements Codec.
+func (JSONCodec[V]) Encode(v V) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode implements Codec.
+func (JSONCodec[V]) Decode(data []byte) (v V, err error) {
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
@@ -1,11 +1,13 @@
 package kocache
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/pkg/errors"
 )
 
@@ -27,71 +29,251 @@ var (
 
 // Cache is single flight cache
 type Cache[K comparable, V any] struct {
-	cache *lru.Cache[K, *entry[V]]
-	opts  options
-	stats Stats
+	mu            sync.Mutex
+	items         map[K]*entry[V]
+	policy        EvictionPolicy[K]
+	weight        int
+	opts          options[K, V]
+	stats         Stats
+	store         Store
+	storePolicy   EvictionPolicy[string]
+	storeExpireAt map[string]time.Time // persisted expiry for store-backed entries, guarded by mu
+
+	expQ           *expQueue[K, V]
+	expIndex       map[K]*expItem[K, V]
+	janitorWake    chan struct{}
+	janitorStopped chan struct{}
+	janitorDone    chan struct{}
+
+	events        chan Event[K, V]
+	eventsStopped chan struct{}
+	eventsDone    chan struct{}
+	eventsOnce    sync.Once // starts dispatchEvents lazily, from the first Subscribe or from Stop
+	subsMu        sync.RWMutex
+	subs          map[uint64]func(Event[K, V])
+	subSeq        uint64
+
+	stopOnce sync.Once
 }
 
 // options describes option list
-type options struct {
-	size            int
-	withStats       bool
-	defaultLifetime time.Duration
+type options[K comparable, V any] struct {
+	size              int
+	withStats         bool
+	defaultLifetime   time.Duration
+	policy            EvictionPolicy[K]
+	onEvict           func(K, V)
+	store             Store
+	codec             Codec[V]
+	maxBytes          int64
+	slidingExpiration time.Duration
+	janitorInterval   time.Duration
+	clock             Clock
 }
 
-// Stats describes cache hits&misses statistics.
+// Stats describes cache statistics. Every field is updated atomically
+// (BytesInUse with atomic.StoreUint64, the counters with atomic.AddUint64)
+// and may be read concurrently with Stats.
 type Stats struct {
-	Hits   uint32
-	Misses uint32
+	Hits   uint64
+	Misses uint64
+	// Evictions counts entries removed by the eviction policy to make room
+	// for another one.
+	Evictions uint64
+	// Expirations counts expired entries removed by the background janitor
+	// or by a Get that falls through to the store, plus every Get that finds
+	// its in-memory entry expired. Get does not remove such an entry, so
+	// each repeated read of it is counted again.
+	Expirations uint64
+	// Reservations counts calls to Reserve and its variants.
+	Reservations uint64
+	// SingleFlightWaits counts Get calls that blocked waiting for another
+	// caller's in-flight Reserve to resolve.
+	SingleFlightWaits uint64
+	// TimeoutErrors counts GetWithTimeout calls that returned ErrGetCacheTimeout.
+	TimeoutErrors uint64
+	// BytesInUse is the size in bytes of the store configured via WithStore,
+	// kept up to date as entries are written and deleted. It is always 0
+	// when no store is configured.
+	BytesInUse uint64
+	// EventDrops counts events dropped because a subscriber was too slow to
+	// keep up with the buffered channel used by Subscribe.
+	EventDrops uint64
 }
 
 // An Option is an option for a kocache
-type Option interface {
-	Apply(opts *options)
+type Option[K comparable, V any] interface {
+	Apply(opts *options[K, V])
 }
 
 // WithStats returns an Option that enables cache statistics.
-func WithStats() Option {
-	return withStats{}
+func WithStats[K comparable, V any]() Option[K, V] {
+	return withStats[K, V]{}
 }
 
-type withStats struct {
+type withStats[K comparable, V any] struct {
 }
 
-func (w withStats) Apply(opts *options) {
+func (w withStats[K, V]) Apply(opts *options[K, V]) {
 	opts.withStats = true
 }
 
 // WithSize returns an Option that defines cache size.
-func WithSize(size int) Option {
-	return withSize{size}
+func WithSize[K comparable, V any](size int) Option[K, V] {
+	return withSize[K, V]{size}
 }
 
-type withSize struct {
+type withSize[K comparable, V any] struct {
 	size int
 }
 
-func (w withSize) Apply(opts *options) {
+func (w withSize[K, V]) Apply(opts *options[K, V]) {
 	opts.size = w.size
 }
 
 // WithDefaultLifetime returns an Option that defines cache default lifetime.
-func WithDefaultLifetime(defaultLifetime time.Duration) Option {
-	return withDefaultLifetime{defaultLifetime}
+func WithDefaultLifetime[K comparable, V any](defaultLifetime time.Duration) Option[K, V] {
+	return withDefaultLifetime[K, V]{defaultLifetime}
 }
 
-type withDefaultLifetime struct {
+type withDefaultLifetime[K comparable, V any] struct {
 	defaultLifetime time.Duration
 }
 
-func (w withDefaultLifetime) Apply(opts *options) {
+func (w withDefaultLifetime[K, V]) Apply(opts *options[K, V]) {
 	opts.defaultLifetime = w.defaultLifetime
 }
 
+// WithEvictionPolicy returns an Option that replaces the default LRU
+// eviction policy. See EvictionPolicy, NewLRUPolicy, NewLFUPolicy and
+// NewSLRUPolicy.
+func WithEvictionPolicy[K comparable, V any](policy EvictionPolicy[K]) Option[K, V] {
+	return withEvictionPolicy[K, V]{policy}
+}
+
+type withEvictionPolicy[K comparable, V any] struct {
+	policy EvictionPolicy[K]
+}
+
+func (w withEvictionPolicy[K, V]) Apply(opts *options[K, V]) {
+	opts.policy = w.policy
+}
+
+// WithOnEvict returns an Option that registers a callback invoked whenever
+// an entry is evicted from the cache, e.g. to release resources held by the
+// evicted value. fn runs after the eviction has released the Cache's
+// internal lock, so it may safely call back into the same Cache, but it is
+// still called synchronously from the Reserve/janitor goroutine that
+// triggered the eviction: a slow fn delays that caller (or the janitor)
+// until it returns.
+func WithOnEvict[K comparable, V any](fn func(K, V)) Option[K, V] {
+	return withOnEvict[K, V]{fn}
+}
+
+type withOnEvict[K comparable, V any] struct {
+	fn func(K, V)
+}
+
+func (w withOnEvict[K, V]) Apply(opts *options[K, V]) {
+	opts.onEvict = w.fn
+}
+
+// WithStore returns an Option that gives the Cache an overflow tier: values
+// are written through to store as they are resolved, so a Get for a key the
+// in-memory index has since evicted can still be served from store. Requires
+// WithCodec to also be set, so values can be serialized.
+func WithStore[K comparable, V any](store Store) Option[K, V] {
+	return withStore[K, V]{store}
+}
+
+type withStore[K comparable, V any] struct {
+	store Store
+}
+
+func (w withStore[K, V]) Apply(opts *options[K, V]) {
+	opts.store = w.store
+}
+
+// WithCodec returns an Option that sets the Codec used to serialize values
+// for the store configured by WithStore.
+func WithCodec[K comparable, V any](codec Codec[V]) Option[K, V] {
+	return withCodec[K, V]{codec}
+}
+
+type withCodec[K comparable, V any] struct {
+	codec Codec[V]
+}
+
+func (w withCodec[K, V]) Apply(opts *options[K, V]) {
+	opts.codec = w.codec
+}
+
+// WithMaxBytes returns an Option that bounds the store configured by
+// WithStore to n bytes: whenever store.Size() exceeds n after a write, the
+// least recently used store entries are deleted until it no longer does.
+func WithMaxBytes[K comparable, V any](n int64) Option[K, V] {
+	return withMaxBytes[K, V]{n}
+}
+
+type withMaxBytes[K comparable, V any] struct {
+	n int64
+}
+
+func (w withMaxBytes[K, V]) Apply(opts *options[K, V]) {
+	opts.maxBytes = w.n
+}
+
+// WithSlidingExpiration returns an Option that resets an entry's expiration
+// to d from now every time it is successfully read via Get or GetWithTimeout,
+// instead of expiring a fixed duration after it was resolved.
+func WithSlidingExpiration[K comparable, V any](d time.Duration) Option[K, V] {
+	return withSlidingExpiration[K, V]{d}
+}
+
+type withSlidingExpiration[K comparable, V any] struct {
+	d time.Duration
+}
+
+func (w withSlidingExpiration[K, V]) Apply(opts *options[K, V]) {
+	opts.slidingExpiration = w.d
+}
+
+// WithJanitor returns an Option that starts a background goroutine removing
+// expired entries every interval (woken early whenever a sooner deadline is
+// scheduled), instead of relying on eviction or a later Get to notice them.
+// Call Stop on the Cache to shut the goroutine down.
+func WithJanitor[K comparable, V any](interval time.Duration) Option[K, V] {
+	return withJanitor[K, V]{interval}
+}
+
+type withJanitor[K comparable, V any] struct {
+	interval time.Duration
+}
+
+func (w withJanitor[K, V]) Apply(opts *options[K, V]) {
+	opts.janitorInterval = w.interval
+}
+
+// WithClock returns an Option that replaces the Cache's default wall-clock
+// Clock, e.g. with a fakeclock.Clock for deterministic tests that would
+// otherwise depend on real sleeps.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return withClock[K, V]{clock}
+}
+
+type withClock[K comparable, V any] struct {
+	clock Clock
+}
+
+func (w withClock[K, V]) Apply(opts *options[K, V]) {
+	opts.clock = w.clock
+}
+
 // New creates a new Cache.
-func New[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
+func New[K comparable, V any](opts ...Option[K, V]) (*Cache[K, V], error) {
 	c := &Cache[K, V]{
-		opts: options{
+		items: make(map[K]*entry[V]),
+		opts: options[K, V]{
 			size:            DefaultSize,
 			withStats:       false,
 			defaultLifetime: -1, // no expiration
@@ -102,12 +284,43 @@ func New[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
 		opt.Apply(&c.opts)
 	}
 
-	inner, err := lru.New[K, *entry[V]](c.opts.size)
-	if err != nil {
-		return nil, err
+	if c.opts.size <= 0 {
+		return nil, errors.New("size must be greater than 0")
+	}
+
+	if c.opts.clock == nil {
+		c.opts.clock = realClock{}
+	}
+
+	if c.opts.policy == nil {
+		c.opts.policy = NewLRUPolicy[K]()
+	}
+	c.policy = c.opts.policy
+
+	if c.opts.store != nil {
+		if c.opts.codec == nil {
+			return nil, errors.New("codec is required when a store is configured")
+		}
+		c.store = c.opts.store
+		c.storePolicy = NewLRUPolicy[string]()
+		c.storeExpireAt = make(map[string]time.Time)
+	}
+
+	if c.opts.janitorInterval > 0 {
+		c.expQ = &expQueue[K, V]{}
+		c.expIndex = make(map[K]*expItem[K, V])
+		c.janitorWake = make(chan struct{}, 1)
+		c.janitorStopped = make(chan struct{})
+		c.janitorDone = make(chan struct{})
+		go c.runJanitor(c.opts.janitorInterval)
 	}
 
-	c.cache = inner
+	// The event dispatcher goroutine itself is started lazily, by
+	// startEventDispatcher, so a Cache that never calls Subscribe doesn't
+	// carry a background goroutine for its entire lifetime.
+	c.events = make(chan Event[K, V], DefaultEventBufferSize)
+	c.eventsStopped = make(chan struct{})
+	c.eventsDone = make(chan struct{})
 
 	return c, nil
 }
@@ -123,22 +336,162 @@ func (c *Cache[K, V]) Get(key K) (value V, err error) {
 func (c *Cache[K, V]) GetWithTimeout(key K, timeout time.Duration) (value V, err error) {
 	entity := c.getEntry(key)
 	if entity == nil {
+		if c.store != nil {
+			return c.getFromStore(key)
+		}
+		c.publish(EventMiss, key, value)
 		return value, ErrEntryNotFound
 	}
-	if entity.Expired(time.Now()) {
+	if entity.Expired(c.opts.clock.Now()) {
+		c.addStat(&c.stats.Expirations, 1)
+		c.publish(EventExpire, key, value)
 		return value, ErrExpired
 	}
-	return entity.getWithTimeout(timeout)
+
+	if entity.willWait() {
+		c.addStat(&c.stats.SingleFlightWaits, 1)
+	}
+
+	value, err = entity.getWithTimeout(timeout, c.opts.clock)
+	if err == ErrGetCacheTimeout {
+		c.addStat(&c.stats.TimeoutErrors, 1)
+	}
+	if err == nil && c.opts.slidingExpiration > 0 {
+		entity.touch(c.opts.clock.Now(), c.opts.slidingExpiration)
+		c.updateStoreExpiry(key, entity.expireAtSnapshot())
+		if c.expQ != nil {
+			c.scheduleExpiry(key, entity)
+		}
+	}
+
+	if err == nil {
+		c.publish(EventHit, key, value)
+	}
+
+	return value, err
+}
+
+// Touch resets key's expiration to extend from now, as if it had just been
+// resolved with that lifetime. It returns false if key is not present.
+func (c *Cache[K, V]) Touch(key K, extend time.Duration) bool {
+	c.mu.Lock()
+	ent, ok := c.items[key]
+	if ok {
+		c.policy.OnAccess(key)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	ent.touch(c.opts.clock.Now(), extend)
+	c.updateStoreExpiry(key, ent.expireAtSnapshot())
+
+	if c.expQ != nil {
+		c.scheduleExpiry(key, ent)
+	}
+
+	return true
+}
+
+// updateStoreExpiry keeps storeExpireAt in sync with an entry's expireAt
+// after it is extended post-resolve by Touch or sliding expiration, so that
+// getFromStore doesn't judge the entry against the stale deadline putToStore
+// persisted when it was first resolved.
+func (c *Cache[K, V]) updateStoreExpiry(key K, expireAt time.Time) {
+	if c.store == nil {
+		return
+	}
+
+	sk := storeKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expireAt.IsZero() {
+		delete(c.storeExpireAt, sk)
+	} else {
+		c.storeExpireAt[sk] = expireAt
+	}
+}
+
+// getFromStore serves a Get for a key the in-memory index no longer holds by
+// reading it back from the store configured via WithStore. It honors the
+// expireAt persisted by putToStore, so an entry evicted from the hot index
+// before its TTL elapsed doesn't get served stale forever.
+func (c *Cache[K, V]) getFromStore(key K) (value V, err error) {
+	sk := storeKey(key)
+
+	c.mu.Lock()
+	expireAt, hasExpiry := c.storeExpireAt[sk]
+	c.mu.Unlock()
+
+	if hasExpiry && c.opts.clock.Now().After(expireAt) {
+		c.mu.Lock()
+		c.storePolicy.OnRemove(sk)
+		delete(c.storeExpireAt, sk)
+		_ = c.store.Delete(sk)
+		atomic.StoreUint64(&c.stats.BytesInUse, uint64(c.store.Size()))
+		c.mu.Unlock()
+
+		c.addStat(&c.stats.Expirations, 1)
+		c.publish(EventExpire, key, value)
+		return value, ErrExpired
+	}
+
+	rc, err := c.store.Get(sk)
+	if err != nil {
+		c.publish(EventMiss, key, value)
+		return value, ErrEntryNotFound
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return value, errors.Wrap(err, "kocache: reading store value")
+	}
+
+	value, err = c.opts.codec.Decode(data)
+	if err != nil {
+		return value, errors.Wrap(err, "kocache: decoding store value")
+	}
+
+	c.mu.Lock()
+	c.storePolicy.OnAccess(sk)
+	c.mu.Unlock()
+
+	c.publish(EventHit, key, value)
+
+	return value, nil
+}
+
+// storeKey renders a cache key as the string key a Store is addressed by.
+func storeKey[K comparable](key K) string {
+	return fmt.Sprintf("%v", key)
 }
 
 // Len returns the number of entries in the cache.
 func (c *Cache[K, V]) Len() int {
-	return c.cache.Len()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
 }
 
 // Stats retuns statistics of the cache
 func (c *Cache[K, V]) Stats() Stats {
-	return c.stats
+	return Stats{
+		Hits:              atomic.LoadUint64(&c.stats.Hits),
+		Misses:            atomic.LoadUint64(&c.stats.Misses),
+		Evictions:         atomic.LoadUint64(&c.stats.Evictions),
+		Expirations:       atomic.LoadUint64(&c.stats.Expirations),
+		Reservations:      atomic.LoadUint64(&c.stats.Reservations),
+		SingleFlightWaits: atomic.LoadUint64(&c.stats.SingleFlightWaits),
+		TimeoutErrors:     atomic.LoadUint64(&c.stats.TimeoutErrors),
+		BytesInUse:        atomic.LoadUint64(&c.stats.BytesInUse),
+		EventDrops:        atomic.LoadUint64(&c.stats.EventDrops),
+	}
 }
 
 // ResolveFunc describes function which resolves cache.
@@ -155,75 +508,279 @@ func (c *Cache[K, V]) Reserve(key K) ResolveFunc[V] {
 // Caller must try fetch the value and call resolveFunc to set result, otherwise others will wait until timeout.
 // ReserveWithLifetime  must be called jsut once. It will panic if called two or more times.
 func (c *Cache[K, V]) ReserveWithLifetime(key K, lifetime time.Duration) ResolveFunc[V] {
-	entry := &entry[V]{lock: make(chan struct{})}
+	return c.reserve(key, lifetime, 1)
+}
 
-	var mux sync.Mutex
-	reserved := false
+// ReserveWithWeight reserves cache entry to fetch, consuming weight capacity
+// units instead of the default 1. Use this together with WithEvictionPolicy
+// and WithSize when cached values vary a lot in cost, so that a handful of
+// large entries can't silently starve the cache of slots for small ones.
+func (c *Cache[K, V]) ReserveWithWeight(key K, weight int) ResolveFunc[V] {
+	return c.reserve(key, c.opts.defaultLifetime, weight)
+}
+
+// ReserveWithExpireAt reserves a cache entry that expires at the given
+// absolute time, rather than a lifetime computed from when it is resolved.
+// The returned ResolveFunc must be called just once. It will panic if called
+// two or more times.
+func (c *Cache[K, V]) ReserveWithExpireAt(key K, at time.Time) ResolveFunc[V] {
+	ent := &entry[V]{lock: make(chan struct{}), weight: 1}
+	return c.register(key, ent, func() {
+		ent.expireAt = at
+	})
+}
+
+func (c *Cache[K, V]) reserve(key K, lifetime time.Duration, weight int) ResolveFunc[V] {
+	if weight < 1 {
+		weight = 1
+	}
+
+	ent := &entry[V]{lock: make(chan struct{}), weight: weight}
+
+	return c.register(key, ent, func() {
+		if lifetime >= 0 {
+			ent.expireAt = c.opts.clock.Now().Add(lifetime)
+		}
+	})
+}
 
+// register wires up ent's resolve func and adds ent to the cache, returning
+// the resolve func callers use to fulfil the reservation. onResolve, if
+// given, runs with ent.mu held right before ent.lock is closed - this is the
+// same lock path Touch and sliding expiration use to update ent.expireAt, so
+// concurrent Get calls always observe a monotonic deadline.
+func (c *Cache[K, V]) register(key K, ent *entry[V], onResolve func()) ResolveFunc[V] {
 	resolve := func(entity V, err error) {
-		mux.Lock()
-		defer mux.Unlock()
+		ent.mu.Lock()
 
-		if reserved {
+		if ent.reserved {
+			ent.mu.Unlock()
 			panic("already reserved")
 		}
-		reserved = true
+		ent.reserved = true
 
-		entry.value, entry.err = entity, err
+		ent.value, ent.err = entity, err
 
-		if lifetime >= 0 {
-			entry.expireAt = time.Now().Add(lifetime)
+		if onResolve != nil {
+			onResolve()
+		}
+
+		close(ent.lock)
+		ent.lock = nil // set nil to save memory
+
+		ent.mu.Unlock()
+
+		if err == nil && c.store != nil {
+			c.putToStore(key, entity, ent.expireAtSnapshot())
+		}
+
+		if c.expQ != nil {
+			c.scheduleExpiry(key, ent)
 		}
 
-		close(entry.lock)
-		entry.lock = nil // set nil to save memory
+		if err == nil {
+			c.publish(EventResolve, key, entity)
+		}
 	}
 
-	c.cache.Add(key, entry)
+	c.mu.Lock()
+	wake, evicted := c.addLocked(key, ent)
+	c.mu.Unlock()
+
+	c.notifyEvicted(evicted)
+
+	c.addStat(&c.stats.Reservations, 1)
+
+	if wake {
+		c.wakeJanitor()
+	}
 
 	return resolve
 }
 
-func (c *Cache[K, V]) getEntry(key K) *entry[V] {
-	v, ok := c.cache.Get(key)
+// evictedEntry pairs a key and value dropped from the hot index, letting
+// addLocked and removeExpired hand their eviction side effects back to a
+// caller that has already released c.mu.
+type evictedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
 
-	if c.opts.withStats {
-		if ok {
-			atomic.AddUint32(&c.stats.Hits, 1)
-		} else {
-			atomic.AddUint32(&c.stats.Misses, 1)
+// notifyEvicted invokes opts.onEvict for each entry in evicted. Callers must
+// not hold c.mu: onEvict is user code and must not run while it could stall
+// every other Get/Reserve on the cache.
+func (c *Cache[K, V]) notifyEvicted(evicted []evictedEntry[K, V]) {
+	if c.opts.onEvict == nil {
+		return
+	}
+	for _, kv := range evicted {
+		c.opts.onEvict(kv.key, kv.value)
+	}
+}
+
+// putToStore writes value through to the store configured via WithStore,
+// persists expireAt (zero meaning no expiration) so a later getFromStore can
+// tell a stale blob from a live one, then trims the store down to
+// opts.maxBytes (when set) by deleting the least recently used entries.
+// Store errors are not fatal: the value remains available from the
+// in-memory index until it is evicted.
+func (c *Cache[K, V]) putToStore(key K, value V, expireAt time.Time) {
+	data, err := c.opts.codec.Encode(value)
+	if err != nil {
+		return
+	}
+
+	sk := storeKey(key)
+	if err := c.store.Put(sk, bytes.NewReader(data)); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.storePolicy.OnAdd(sk, 1)
+	if expireAt.IsZero() {
+		delete(c.storeExpireAt, sk)
+	} else {
+		c.storeExpireAt[sk] = expireAt
+	}
+
+	if c.opts.maxBytes > 0 {
+		for c.store.Size() > c.opts.maxBytes {
+			evictKey, ok := c.storePolicy.Evict()
+			if !ok {
+				break
+			}
+			_ = c.store.Delete(evictKey)
+			delete(c.storeExpireAt, evictKey)
 		}
 	}
 
+	atomic.StoreUint64(&c.stats.BytesInUse, uint64(c.store.Size()))
+}
+
+// addLocked inserts ent under key, replacing and accounting for any existing
+// entry, then evicts via c.policy until the cache is back within capacity.
+// It returns the entries evicted this way, for the caller to pass to
+// notifyEvicted once c.mu is released, and true if ent is now the earliest
+// entry due to expire, meaning the janitor (if any) should be woken. c.mu
+// must be held.
+func (c *Cache[K, V]) addLocked(key K, ent *entry[V]) (wake bool, evicted []evictedEntry[K, V]) {
+	old, replacing := c.items[key]
+	if replacing {
+		c.weight -= old.weight
+		c.policy.OnRemove(key)
+		c.unscheduleExpiryLocked(key)
+	}
+
+	c.items[key] = ent
+	c.weight += ent.weight
+	c.policy.OnAdd(key, ent.weight)
+
+	if !replacing {
+		c.publish(EventInsert, key, ent.value)
+	}
+
+	for c.weight > c.opts.size {
+		evictKey, ok := c.policy.Evict()
+		if !ok {
+			break
+		}
+
+		ev, ok := c.items[evictKey]
+		if !ok {
+			continue
+		}
+
+		delete(c.items, evictKey)
+		c.weight -= ev.weight
+		c.unscheduleExpiryLocked(evictKey)
+
+		evicted = append(evicted, evictedEntry[K, V]{key: evictKey, value: ev.value})
+
+		// The backing blob, if any, is left in the store: capacity eviction
+		// only drops the entry from the hot in-memory index, so a later Get
+		// still falls through to getFromStore. The store's own byte budget
+		// (WithMaxBytes) is enforced independently in putToStore.
+
+		c.addStat(&c.stats.Evictions, 1)
+		c.publish(EventEvict, evictKey, ev.value)
+	}
+
+	if c.expQ != nil {
+		wake = c.scheduleExpiryLocked(key, ent)
+	}
+
+	return wake, evicted
+}
+
+// addStat adds delta to *p if stats are enabled via WithStats.
+func (c *Cache[K, V]) addStat(p *uint64, delta uint64) {
+	if c.opts.withStats {
+		atomic.AddUint64(p, delta)
+	}
+}
+
+func (c *Cache[K, V]) getEntry(key K) *entry[V] {
+	c.mu.Lock()
+	ent, ok := c.items[key]
+	if ok {
+		c.policy.OnAccess(key)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.addStat(&c.stats.Hits, 1)
+	} else {
+		c.addStat(&c.stats.Misses, 1)
+	}
+
 	if !ok {
 		return nil
 	}
 
-	return v
+	return ent
 }
 
 // entry is cache entry.
 type entry[V any] struct {
+	mu       sync.Mutex    // guards reserved and expireAt
 	lock     chan struct{} // lock for fetch
 	value    V
 	err      error
 	expireAt time.Time // zero means no-expiration
+	weight   int       // capacity units this entry consumes
+	reserved bool
+}
+
+// touch resets the entry's expiration to d from now.
+func (ce *entry[V]) touch(now time.Time, d time.Duration) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
+	ce.expireAt = now.Add(d)
 }
 
 // get gets cache.
-func (ce *entry[V]) get() (V, error) {
-	return ce.getWithTimeout(-1)
+func (ce *entry[V]) get(clock Clock) (V, error) {
+	return ce.getWithTimeout(-1, clock)
+}
+
+// willWait reports whether a call to getWithTimeout would block waiting for
+// another caller's Reserve to resolve, rather than returning immediately.
+func (ce *entry[V]) willWait() bool {
+	return ce.lock != nil
 }
 
 // getWithTimeout gets cache indicating timeout.
-func (ce *entry[V]) getWithTimeout(timeout time.Duration) (v V, err error) {
+func (ce *entry[V]) getWithTimeout(timeout time.Duration, clock Clock) (v V, err error) {
 	if lock := ce.lock; lock != nil { // nil lock means cache is ready
 		if timeout < 0 { // no timeout
 			<-lock
 		} else {
 			select {
 			case <-lock:
-			case <-time.After(timeout):
+			case <-clock.After(timeout):
 				return v, ErrGetCacheTimeout
 			}
 		}
@@ -238,5 +795,17 @@ func (ce *entry[V]) getWithTimeout(timeout time.Duration) (v V, err error) {
 
 // Expired returns true if cache expired.
 func (ce *entry[V]) Expired(now time.Time) bool {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
 	return !ce.expireAt.IsZero() && now.After(ce.expireAt)
 }
+
+// expireAtSnapshot returns the entry's current expireAt under its lock, so
+// callers (such as putToStore) can read it without racing entry.touch.
+func (ce *entry[V]) expireAtSnapshot() time.Time {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
+	return ce.expireAt
+}
@@ -0,0 +1,76 @@
+package kocache
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MemStore is an in-memory Store. It is mainly useful for tests and for
+// Cache configurations that want byte-capacity accounting without touching
+// disk.
+type MemStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	size int64
+}
+
+// NewMemStore creates a new, empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{data: make(map[string][]byte)}
+}
+
+// Get implements Store.
+func (s *MemStore) Get(key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.data[key]
+	if !ok {
+		return nil, ErrEntryNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Put implements Store.
+func (s *MemStore) Put(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "kocache: reading value for MemStore")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.data[key]; ok {
+		s.size -= int64(len(old))
+	}
+	s.data[key] = data
+	s.size += int64(len(data))
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.data[key]; ok {
+		s.size -= int64(len(old))
+		delete(s.data, key)
+	}
+
+	return nil
+}
+
+// Size implements Store.
+func (s *MemStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
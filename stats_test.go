@@ -0,0 +1,87 @@
+package kocache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knightso/kocache/fakeclock"
+)
+
+func TestStatsCountersWithStatsEnabled(t *testing.T) {
+	cache, err := New(WithStats[string, string](), WithSize[string, string](1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("a")("1", nil)
+	if _, err := cache.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get("missing"); err != ErrEntryNotFound {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+	cache.Reserve("b")("2", nil) // evicts "a", since size is 1
+
+	stats := cache.Stats()
+	if stats.Hits != 1 {
+		t.Errorf("expected 1 hit, got %d", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("expected 1 miss, got %d", stats.Misses)
+	}
+	if stats.Reservations != 2 {
+		t.Errorf("expected 2 reservations, got %d", stats.Reservations)
+	}
+	if stats.Evictions != 1 {
+		t.Errorf("expected 1 eviction, got %d", stats.Evictions)
+	}
+}
+
+func TestStatsCountersWithoutWithStatsStayZero(t *testing.T) {
+	cache, err := New[string, string]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("a")("1", nil)
+	if _, err := cache.Get("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 0 || stats.Reservations != 0 {
+		t.Fatalf("expected counters to stay 0 without WithStats, got %+v", stats)
+	}
+}
+
+func TestStatsTimeoutErrors(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(WithStats[string, string](), WithClock[string, string](clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("key") // never resolved
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := cache.GetWithTimeout("key", time.Millisecond)
+		result <- err
+	}()
+
+	// give the goroutine a chance to start waiting on the fake clock's After
+	// before advancing it past the timeout.
+	time.Sleep(10 * time.Millisecond)
+	clock.Advance(time.Millisecond)
+
+	if err := <-result; err != ErrGetCacheTimeout {
+		t.Fatalf("expected ErrGetCacheTimeout, got %v", err)
+	}
+
+	if stats := cache.Stats(); stats.TimeoutErrors != 1 || stats.SingleFlightWaits != 1 {
+		t.Fatalf("expected 1 timeout and 1 single-flight wait, got %+v", stats)
+	}
+}
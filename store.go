@@ -0,0 +1,27 @@
+package kocache
+
+import "io"
+
+// Store is a persistent (or at least out-of-process) tier a Cache can
+// overflow into via WithStore. Keys are the string form of the cache's key
+// type; see storeKey.
+type Store interface {
+	// Get returns the stored value for key. Callers must close the
+	// returned ReadCloser. Implementations must return an error if key is
+	// absent.
+	Get(key string) (io.ReadCloser, error)
+	// Put stores the contents of r under key, replacing any existing value.
+	Put(key string, r io.Reader) error
+	// Delete removes key from the store. It is not an error to delete a
+	// missing key.
+	Delete(key string) error
+	// Size returns the total number of bytes currently held by the store.
+	Size() int64
+}
+
+// Codec encodes and decodes cache values to and from bytes so that they can
+// be written to a Store.
+type Codec[V any] interface {
+	Encode(v V) ([]byte, error)
+	Decode(data []byte) (V, error)
+}
@@ -0,0 +1,234 @@
+package kocache
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/knightso/kocache/fakeclock"
+)
+
+func testStore(t *testing.T, newStore func() Store) {
+	t.Helper()
+
+	store := newStore()
+
+	if err := store.Put("a", strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("b", strings.NewReader("world!")); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual, expected := store.Size(), int64(len("hello")+len("world!")); actual != expected {
+		t.Fatalf("Size - expected:%d, but was:%d", expected, actual)
+	}
+
+	assertGet(t, store, "a", "hello")
+	assertGet(t, store, "b", "world!")
+
+	// overwriting "a" with a shorter value must adjust Size() accordingly.
+	if err := store.Put("a", strings.NewReader("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := store.Size(), int64(len("hi")+len("world!")); actual != expected {
+		t.Fatalf("Size after overwrite - expected:%d, but was:%d", expected, actual)
+	}
+	assertGet(t, store, "a", "hi")
+
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := store.Size(), int64(len("world!")); actual != expected {
+		t.Fatalf("Size after delete - expected:%d, but was:%d", expected, actual)
+	}
+
+	if _, err := store.Get("a"); err != ErrEntryNotFound {
+		t.Fatalf("ErrEntryNotFound expected, but was:%v", err)
+	}
+
+	// deleting a missing key is not an error.
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	testStore(t, func() Store { return NewMemStore() })
+}
+
+func TestFSStore(t *testing.T) {
+	dir := t.TempDir()
+
+	testStore(t, func() Store {
+		store, err := NewFSStore(filepath.Join(dir, "cache"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	})
+}
+
+func TestFSStoreReopenSeedsSize(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("a", strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, expected := reopened.Size(), int64(len("hello")); actual != expected {
+		t.Fatalf("expected:%d, but was:%d", expected, actual)
+	}
+}
+
+func TestCacheWithStoreServesHotEvictedEntries(t *testing.T) {
+	cache, err := New(
+		WithSize[string, string](1),
+		WithStore[string, string](NewMemStore()),
+		WithCodec[string, string](JSONCodec[string]{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("a")("value-a", nil)
+	cache.Reserve("b")("value-b", nil) // evicts "a" from the hot index, size is 1
+
+	if actual, expected := cache.Len(), 1; actual != expected {
+		t.Fatalf("expected:%d, but was:%d", expected, actual)
+	}
+
+	// "a" is gone from the hot index, but its blob must still be in the
+	// store, since capacity eviction is not a real expiration.
+	value, err := cache.Get("a")
+	if err != nil {
+		t.Fatalf("expected \"a\" to still be servable from the store, but was:%v", err)
+	}
+	if actual, expected := value, "value-a"; actual != expected {
+		t.Fatalf("expected:%s, but was:%s", expected, actual)
+	}
+}
+
+func TestCacheWithStoreExpiredEntryNotServedStaleAfterHotEviction(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(
+		WithSize[string, string](1),
+		WithStore[string, string](NewMemStore()),
+		WithCodec[string, string](JSONCodec[string]{}),
+		WithClock[string, string](clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.ReserveWithLifetime("a", 10*time.Millisecond)("value-a", nil)
+	cache.Reserve("b")("value-b", nil) // evicts "a" from the hot index
+
+	clock.Advance(11 * time.Millisecond)
+
+	if _, err := cache.Get("a"); err != ErrExpired {
+		t.Fatalf("expected ErrExpired, but was:%v", err)
+	}
+}
+
+func TestCacheWithStoreTouchExtendsPersistedExpiry(t *testing.T) {
+	clock := fakeclock.New(time.Unix(0, 0))
+	cache, err := New(
+		WithSize[string, string](1),
+		WithStore[string, string](NewMemStore()),
+		WithCodec[string, string](JSONCodec[string]{}),
+		WithClock[string, string](clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.ReserveWithLifetime("a", 10*time.Millisecond)("value-a", nil)
+
+	if !cache.Touch("a", time.Hour) {
+		t.Fatal("expected Touch to find \"a\"")
+	}
+
+	cache.Reserve("b")("value-b", nil) // evicts "a" from the hot index
+
+	clock.Advance(20 * time.Millisecond) // past the original TTL, nowhere near the touched one
+
+	value, err := cache.Get("a")
+	if err != nil {
+		t.Fatalf("expected \"a\" to still be servable after Touch extended it, but was:%v", err)
+	}
+	if actual, expected := value, "value-a"; actual != expected {
+		t.Fatalf("expected:%s, but was:%s", expected, actual)
+	}
+}
+
+func TestCacheWithMaxBytesTrimsStore(t *testing.T) {
+	store := NewMemStore()
+	cache, err := New(
+		WithSize[string, string](1), // evicts from the hot index on every Reserve, forcing store fallback
+		WithStore[string, string](store),
+		WithCodec[string, string](JSONCodec[string]{}),
+		WithMaxBytes[string, string](10), // fits one encoded value ("value-x" -> 9 bytes), not two
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+
+	cache.Reserve("a")("value-a", nil)
+	cache.Reserve("b")("value-b", nil) // pushes store over budget: "a" is trimmed as the LRU blob
+	cache.Reserve("c")("value-c", nil) // same for "b"
+
+	if got, want := uint64(cache.Stats().BytesInUse), uint64(store.Size()); got != want {
+		t.Fatalf("BytesInUse - expected:%d, but was:%d", want, got)
+	}
+	if actual, expected := store.Size(), int64(len(`"value-c"`)); actual != expected {
+		t.Fatalf("store.Size() after trimming - expected:%d, but was:%d", expected, actual)
+	}
+
+	if _, err := cache.Get("a"); err != ErrEntryNotFound {
+		t.Fatalf("expected \"a\" to have been trimmed from the store, but was:%v", err)
+	}
+	if _, err := cache.Get("b"); err != ErrEntryNotFound {
+		t.Fatalf("expected \"b\" to have been trimmed from the store, but was:%v", err)
+	}
+
+	value, err := cache.Get("c")
+	if err != nil {
+		t.Fatalf("expected \"c\" to still be servable, but was:%v", err)
+	}
+	if actual, expected := value, "value-c"; actual != expected {
+		t.Fatalf("expected:%s, but was:%s", expected, actual)
+	}
+}
+
+func assertGet(t *testing.T, store Store, key, expected string) {
+	t.Helper()
+
+	rc, err := store.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := string(data); actual != expected {
+		t.Fatalf("expected:%s, but was:%s", expected, actual)
+	}
+}